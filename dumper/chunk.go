@@ -0,0 +1,375 @@
+package dumper
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultRowsPerChunk is used when RowsPerChunk is not provided.
+const DefaultRowsPerChunk = 100000
+
+// chunkRange describes one slice of a table's data to be fetched by a
+// single worker. Numeric primary keys use where (a "pk >= x AND pk < y"
+// fragment); composite or non-numeric keys fall back to orderBy/limit/offset.
+type chunkRange struct {
+	index     int
+	where     string
+	useOffset bool
+	orderBy   string
+	limit     uint64
+	offset    uint64
+}
+
+// chunkResult is the output of dumping a single chunk, tagged with its
+// index so the serializer can emit chunks in the original table order.
+type chunkResult struct {
+	index int
+	buf   *bytes.Buffer
+	err   error
+}
+
+// writeTableDataConcurrent dumps a table's rows using d.Concurrency workers,
+// each fetching a distinct chunk of rows, and serializes their output back
+// into w in chunk order so the resulting dump is deterministic.
+func (d *Client) writeTableDataConcurrent(w io.Writer, table string) error {
+	chunks, err := d.buildChunks(table)
+	if err != nil {
+		return err
+	}
+
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	workers := d.Concurrency
+	if workers > len(chunks) {
+		workers = len(chunks)
+	}
+
+	jobs := make(chan chunkRange)
+	results := make(chan chunkResult, len(chunks))
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				buf, err := d.dumpChunk(table, job)
+				results <- chunkResult{index: job.index, buf: buf, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, c := range chunks {
+			jobs <- c
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return d.serializeChunkResults(w, results)
+}
+
+// serializeChunkResults buffers out-of-order chunk results and flushes them
+// to w strictly in index order, so concurrent dumping still produces a
+// deterministic, diffable output.
+func (d *Client) serializeChunkResults(w io.Writer, results <-chan chunkResult) error {
+	pending := make(map[int]*bytes.Buffer)
+	next := 0
+	var firstErr error
+
+	for res := range results {
+		if res.err != nil && firstErr == nil {
+			firstErr = res.err
+		}
+
+		pending[res.index] = res.buf
+
+		for {
+			buf, ok := pending[next]
+			if !ok {
+				break
+			}
+
+			if firstErr == nil && buf != nil {
+				if _, err := w.Write(buf.Bytes()); err != nil {
+					firstErr = err
+				}
+			}
+
+			delete(pending, next)
+			next++
+		}
+	}
+
+	return firstErr
+}
+
+// dumpChunk runs the SELECT for a single chunk and renders its rows into an
+// independent buffer, so workers never contend on the destination writer.
+func (d *Client) dumpChunk(table string, job chunkRange) (*bytes.Buffer, error) {
+	query, err := d.chunkSelectQuery(table, job)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := d.query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := d.writeRowsAsInserts(buf, rows, columns, table, d.newChunkFormat()); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// chunkSelectQuery builds the SELECT for a single chunk, combining the
+// chunk's own row-range condition with any configured WhereMap filter and,
+// for offset-based chunks, an ORDER BY/LIMIT/OFFSET clause.
+func (d *Client) chunkSelectQuery(table string, job chunkRange) (string, error) {
+	cols, err := d.GetColumnsForSelect(table)
+	if err != nil {
+		return "", err
+	}
+
+	clauses := make([]string, 0, 2)
+	if job.where != "" {
+		clauses = append(clauses, job.where)
+	}
+	if where, ok := d.WhereMap[strings.ToLower(table)]; ok {
+		clauses = append(clauses, where)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM `%s`", strings.Join(cols, ", "), table)
+	if len(clauses) > 0 {
+		query = fmt.Sprintf("%s WHERE (%s)", query, strings.Join(clauses, ") AND ("))
+	}
+
+	if job.useOffset {
+		if job.orderBy != "" {
+			query = fmt.Sprintf("%s ORDER BY %s", query, job.orderBy)
+		}
+		query = fmt.Sprintf("%s LIMIT %d OFFSET %d", query, job.limit, job.offset)
+	}
+
+	return query, nil
+}
+
+// buildChunks splits a table into chunkRanges for concurrent dumping. A
+// single numeric primary key is split into contiguous pk ranges; anything
+// else (composite or non-numeric keys, or no key at all) falls back to
+// ORDER BY/LIMIT/OFFSET chunking.
+func (d *Client) buildChunks(table string) ([]chunkRange, error) {
+	pk, err := d.queryPrimaryKeyColumns(table)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pk) == 1 {
+		numeric, err := d.isNumericColumn(table, pk[0])
+		if err != nil {
+			return nil, err
+		}
+		if numeric {
+			return d.buildRangeChunks(table, pk[0])
+		}
+	}
+
+	return d.buildOffsetChunks(table, pk)
+}
+
+// buildRangeChunks splits [MIN(pk), MAX(pk)] into contiguous ranges that
+// each span RowsPerChunk key values, so a range holds fewer rows where the
+// keys have gaps.
+func (d *Client) buildRangeChunks(table, pk string) ([]chunkRange, error) {
+	query := fmt.Sprintf("SELECT MIN(`%s`), MAX(`%s`) FROM `%s`", pk, pk, table)
+	if where, ok := d.WhereMap[strings.ToLower(table)]; ok {
+		query = fmt.Sprintf("%s WHERE %s", query, where)
+	}
+
+	var min, max sql.NullInt64
+	if err := d.queryRow(query).Scan(&min, &max); err != nil {
+		return nil, err
+	}
+
+	if !min.Valid || !max.Valid {
+		return nil, nil
+	}
+
+	rowsPerChunk := d.rowsPerChunk()
+
+	chunks := make([]chunkRange, 0)
+	index := 0
+	for start := min.Int64; start <= max.Int64; start += int64(rowsPerChunk) {
+		end := start + int64(rowsPerChunk)
+		chunks = append(chunks, chunkRange{
+			index: index,
+			where: fmt.Sprintf("`%s` >= %d AND `%s` < %d", pk, start, pk, end),
+		})
+		index++
+	}
+
+	return chunks, nil
+}
+
+// buildOffsetChunks splits a table into LIMIT/OFFSET pages ordered by pk,
+// or by every column when the table has no primary key at all. MySQL
+// doesn't guarantee a stable tie-break across separate query executions
+// for a non-unique ORDER BY key, which would let concurrent per-chunk
+// SELECTs silently duplicate or drop rows; ordering by the full row makes
+// the tie-break deterministic as long as no two rows are byte-for-byte
+// identical.
+func (d *Client) buildOffsetChunks(table string, pk []string) ([]chunkRange, error) {
+	count, err := d.GetRowCountForTable(table)
+	if err != nil {
+		return nil, err
+	}
+
+	orderCols := pk
+	if len(orderCols) == 0 {
+		cols, err := d.queryColumnNames(table)
+		if err != nil {
+			return nil, err
+		}
+		orderCols = cols
+	}
+	orderBy := "`" + strings.Join(orderCols, "`, `") + "`"
+
+	rowsPerChunk := d.rowsPerChunk()
+
+	chunks := make([]chunkRange, 0)
+	index := 0
+	for offset := uint64(0); offset < count; offset += rowsPerChunk {
+		chunks = append(chunks, chunkRange{
+			index:     index,
+			useOffset: true,
+			orderBy:   orderBy,
+			limit:     rowsPerChunk,
+			offset:    offset,
+		})
+		index++
+	}
+
+	return chunks, nil
+}
+
+// queryColumnNames returns a table's column names in SELECT * order, used
+// to build a full-row ORDER BY tie-break when a table has no primary key.
+func (d *Client) queryColumnNames(table string) ([]string, error) {
+	rows, err := d.query(fmt.Sprintf("SELECT * FROM `%s` LIMIT 1", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return rows.Columns()
+}
+
+func (d *Client) rowsPerChunk() uint64 {
+	if d.RowsPerChunk == 0 {
+		return DefaultRowsPerChunk
+	}
+	return d.RowsPerChunk
+}
+
+// queryPrimaryKeyColumns returns the primary key columns of a table in
+// index order, or an empty slice if the table has no primary key.
+func (d *Client) queryPrimaryKeyColumns(table string) ([]string, error) {
+	rows, err := d.query(fmt.Sprintf("SHOW KEYS FROM `%s` WHERE Key_name = 'PRIMARY'", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	nameIdx, seqIdx := -1, -1
+	for i, c := range cols {
+		switch c {
+		case "Column_name":
+			nameIdx = i
+		case "Seq_in_index":
+			seqIdx = i
+		}
+	}
+
+	type keyColumn struct {
+		seq  int
+		name string
+	}
+	var keyColumns []keyColumn
+
+	for rows.Next() {
+		values := make([]sql.RawBytes, len(cols))
+		scanArgs := make([]interface{}, len(cols))
+		for i := range values {
+			scanArgs[i] = &values[i]
+		}
+
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, err
+		}
+
+		seq := 0
+		if seqIdx >= 0 {
+			seq, _ = strconv.Atoi(string(values[seqIdx]))
+		}
+
+		keyColumns = append(keyColumns, keyColumn{seq: seq, name: string(values[nameIdx])})
+	}
+
+	sort.Slice(keyColumns, func(i, j int) bool { return keyColumns[i].seq < keyColumns[j].seq })
+
+	names := make([]string, len(keyColumns))
+	for i, kc := range keyColumns {
+		names[i] = kc.name
+	}
+
+	return names, nil
+}
+
+// isNumericColumn reports whether a column's type is a numeric type
+// eligible for MIN/MAX range chunking.
+func (d *Client) isNumericColumn(table, column string) (bool, error) {
+	row := d.queryRow(fmt.Sprintf("SHOW COLUMNS FROM `%s` WHERE Field = '%s'", table, column))
+
+	var field, colType string
+	var null, key, extra, def sql.NullString
+
+	if err := row.Scan(&field, &colType, &null, &key, &def, &extra); err != nil {
+		return false, err
+	}
+
+	colType = strings.ToLower(colType)
+	for _, prefix := range []string{"tinyint", "smallint", "mediumint", "int", "bigint", "decimal", "numeric"} {
+		if strings.HasPrefix(colType, prefix) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
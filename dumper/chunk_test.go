@@ -0,0 +1,152 @@
+package dumper
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildChunksSplitsNumericPrimaryKeyIntoRanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	dumper := NewMySQLDumper(db)
+	dumper.RowsPerChunk = 100
+
+	mock.ExpectQuery("SHOW KEYS FROM `table` WHERE Key_name = 'PRIMARY'").WillReturnRows(
+		sqlmock.NewRows([]string{"Table", "Non_unique", "Key_name", "Seq_in_index", "Column_name"}).
+			AddRow("table", 0, "PRIMARY", 1, "id"),
+	)
+	mock.ExpectQuery("SHOW COLUMNS FROM `table` WHERE Field = 'id'").WillReturnRows(
+		sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+			AddRow("id", "bigint(20)", "NO", "PRI", nil, "auto_increment"),
+	)
+	mock.ExpectQuery("SELECT MIN\\(`id`\\), MAX\\(`id`\\) FROM `table`").WillReturnRows(
+		sqlmock.NewRows([]string{"MIN(`id`)", "MAX(`id`)"}).AddRow(1, 250),
+	)
+
+	chunks, err := dumper.buildChunks("table")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []chunkRange{
+		{index: 0, where: "`id` >= 1 AND `id` < 101"},
+		{index: 1, where: "`id` >= 101 AND `id` < 201"},
+		{index: 2, where: "`id` >= 201 AND `id` < 301"},
+	}, chunks)
+}
+
+func TestBuildChunksFallsBackToOffsetPagingForNonNumericPrimaryKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	dumper := NewMySQLDumper(db)
+	dumper.RowsPerChunk = 2
+
+	mock.ExpectQuery("SHOW KEYS FROM `table` WHERE Key_name = 'PRIMARY'").WillReturnRows(
+		sqlmock.NewRows([]string{"Table", "Non_unique", "Key_name", "Seq_in_index", "Column_name"}).
+			AddRow("table", 0, "PRIMARY", 1, "uuid"),
+	)
+	mock.ExpectQuery("SHOW COLUMNS FROM `table` WHERE Field = 'uuid'").WillReturnRows(
+		sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+			AddRow("uuid", "char(36)", "NO", "PRI", nil, ""),
+	)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `table`").WillReturnRows(
+		sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(5),
+	)
+
+	chunks, err := dumper.buildChunks("table")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []chunkRange{
+		{index: 0, useOffset: true, orderBy: "`uuid`", limit: 2, offset: 0},
+		{index: 1, useOffset: true, orderBy: "`uuid`", limit: 2, offset: 2},
+		{index: 2, useOffset: true, orderBy: "`uuid`", limit: 2, offset: 4},
+	}, chunks)
+}
+
+func TestBuildChunksOrdersByEveryColumnWhenTableHasNoPrimaryKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	dumper := NewMySQLDumper(db)
+	dumper.RowsPerChunk = 2
+
+	mock.ExpectQuery("SHOW KEYS FROM `table` WHERE Key_name = 'PRIMARY'").WillReturnRows(
+		sqlmock.NewRows([]string{"Table", "Non_unique", "Key_name", "Seq_in_index", "Column_name"}),
+	)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `table`").WillReturnRows(
+		sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(3),
+	)
+	mock.ExpectQuery("SELECT \\* FROM `table` LIMIT 1").WillReturnRows(
+		sqlmock.NewRows([]string{"user_id", "role"}).AddRow(1, "admin"),
+	)
+
+	chunks, err := dumper.buildChunks("table")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []chunkRange{
+		{index: 0, useOffset: true, orderBy: "`user_id`, `role`", limit: 2, offset: 0},
+		{index: 1, useOffset: true, orderBy: "`user_id`, `role`", limit: 2, offset: 2},
+	}, chunks)
+}
+
+func TestSerializeChunkResultsEmitsInIndexOrderRegardlessOfArrival(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	dumper := NewMySQLDumper(db)
+
+	results := make(chan chunkResult, 3)
+	results <- chunkResult{index: 1, buf: bytes.NewBufferString("b")}
+	results <- chunkResult{index: 0, buf: bytes.NewBufferString("a")}
+	results <- chunkResult{index: 2, buf: bytes.NewBufferString("c")}
+	close(results)
+
+	var out bytes.Buffer
+	assert.Nil(t, dumper.serializeChunkResults(&out, results))
+	assert.Equal(t, "abc", out.String())
+}
+
+func TestSerializeChunkResultsReturnsFirstError(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	dumper := NewMySQLDumper(db)
+
+	boom := errTest("boom")
+	results := make(chan chunkResult, 2)
+	results <- chunkResult{index: 0, buf: bytes.NewBufferString("a")}
+	results <- chunkResult{index: 1, err: boom}
+	close(results)
+
+	var out bytes.Buffer
+	err = dumper.serializeChunkResults(&out, results)
+	assert.Equal(t, boom, err)
+}
+
+func TestNewChunkFormatReturnsDistinctInstancesPerCall(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	dumper := NewMySQLDumper(db)
+	dumper.Format = &CSVFormat{}
+
+	a := dumper.newChunkFormat()
+	b := dumper.newChunkFormat()
+
+	if a == b {
+		t.Fatal("expected newChunkFormat to return a distinct instance per call")
+	}
+}
+
+type errTest string
+
+func (e errTest) Error() string { return string(e) }
@@ -0,0 +1,114 @@
+package dumper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// CSVFormat renders each table as a header row of column names followed by
+// one CSV row per record. Set Dir to write one `<table>.csv` file per table
+// plus a companion `schema.sql` collecting every CREATE TABLE statement —
+// the arrangement LOAD DATA INFILE and other CSV-reading pipelines expect,
+// and the primary mode this format is meant to be used in.
+//
+// Leave Dir empty to fall back to a single stream on the writer passed to
+// WriteTables: every table's rows land in that one stream, so it cannot be
+// fed to LOAD DATA INFILE directly, but each table's section is still
+// marked off by a "# table: <name>" line before its header so the stream
+// stays self-describing. Set Schema in that mode to also capture DDL, as a
+// single companion writer; left nil, WriteSchema is a no-op.
+type CSVFormat struct {
+	// Dir, if set, makes BeginTable write to <Dir>/<table>.csv and
+	// WriteSchema append to <Dir>/schema.sql, ignoring the w passed by
+	// WriteTables entirely.
+	Dir string
+
+	// Schema, used only when Dir is empty, receives CREATE TABLE
+	// statements for each table. Left nil (and Dir empty), WriteSchema is
+	// a no-op so the main stream stays valid CSV.
+	Schema io.Writer
+
+	file   *os.File
+	writer *csv.Writer
+}
+
+// WriteSchema implements Format.
+func (f *CSVFormat) WriteSchema(w io.Writer, table, ddl string) error {
+	if f.Dir != "" {
+		file, err := os.OpenFile(filepath.Join(f.Dir, "schema.sql"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = fmt.Fprintf(file, "-- %s\n%s;\n\n", table, ddl)
+		return err
+	}
+
+	if f.Schema == nil {
+		return nil
+	}
+	_, err := fmt.Fprintf(f.Schema, "-- %s\n%s;\n\n", table, ddl)
+	return err
+}
+
+// BeginTable implements Format.
+func (f *CSVFormat) BeginTable(w io.Writer, table string, columns []string) error {
+	if f.Dir != "" {
+		file, err := os.Create(filepath.Join(f.Dir, table+".csv"))
+		if err != nil {
+			return err
+		}
+		f.file = file
+		f.writer = csv.NewWriter(file)
+	} else {
+		if _, err := fmt.Fprintf(w, "# table: %s\n", table); err != nil {
+			return err
+		}
+		f.writer = csv.NewWriter(w)
+	}
+
+	return f.writer.Write(columns)
+}
+
+// WriteRow implements Format.
+func (f *CSVFormat) WriteRow(w io.Writer, values []interface{}) error {
+	row := make([]string, len(values))
+
+	for i, v := range values {
+		if v != nil {
+			row[i] = v.(string)
+		}
+	}
+
+	return f.writer.Write(row)
+}
+
+// EndTable implements Format.
+func (f *CSVFormat) EndTable(w io.Writer) error {
+	f.writer.Flush()
+	if err := f.writer.Error(); err != nil {
+		return err
+	}
+
+	if f.file != nil {
+		err := f.file.Close()
+		f.file = nil
+		return err
+	}
+
+	return nil
+}
+
+// Finalize implements Format.
+func (f *CSVFormat) Finalize(w io.Writer) error {
+	return nil
+}
+
+// Clone implements Format.
+func (f *CSVFormat) Clone() Format {
+	return &CSVFormat{Dir: f.Dir, Schema: f.Schema}
+}
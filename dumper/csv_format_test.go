@@ -0,0 +1,91 @@
+package dumper
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVFormatWritesTableMarkerHeaderAndRowsToSingleStream(t *testing.T) {
+	var buf bytes.Buffer
+	format := &CSVFormat{}
+
+	assert.Nil(t, format.BeginTable(&buf, "table", []string{"id", "name"}))
+	assert.Nil(t, format.WriteRow(&buf, []interface{}{"1", "alice"}))
+	assert.Nil(t, format.WriteRow(&buf, []interface{}{"2", nil}))
+	assert.Nil(t, format.EndTable(&buf))
+
+	assert.Equal(t, "# table: table\nid,name\n1,alice\n2,\n", buf.String())
+}
+
+func TestCSVFormatWriteSchemaIsNoopWithoutSchemaWriterOrDir(t *testing.T) {
+	var rows bytes.Buffer
+	format := &CSVFormat{}
+
+	assert.Nil(t, format.WriteSchema(&rows, "table", "CREATE TABLE `table` (`id` int)"))
+	assert.Equal(t, "", rows.String())
+}
+
+func TestCSVFormatWriteSchemaRoutesDDLToSchemaWriterNotRows(t *testing.T) {
+	var rows, schema bytes.Buffer
+	format := &CSVFormat{Schema: &schema}
+
+	assert.Nil(t, format.WriteSchema(&rows, "table", "CREATE TABLE `table` (`id` int)"))
+	assert.Equal(t, "", rows.String())
+	assert.Contains(t, schema.String(), "CREATE TABLE `table` (`id` int)")
+}
+
+func TestCSVFormatCloneCarriesSchemaButNotRowState(t *testing.T) {
+	var schema bytes.Buffer
+	format := &CSVFormat{Schema: &schema}
+
+	clone, ok := format.Clone().(*CSVFormat)
+	assert.True(t, ok)
+
+	if clone.Schema != format.Schema {
+		t.Fatal("expected clone to carry the same Schema writer")
+	}
+
+	var buf1, buf2 bytes.Buffer
+	assert.Nil(t, format.BeginTable(&buf1, "a", []string{"id"}))
+	assert.Nil(t, clone.BeginTable(&buf2, "b", []string{"id"}))
+	assert.Nil(t, format.WriteRow(&buf1, []interface{}{"1"}))
+	assert.Nil(t, clone.WriteRow(&buf2, []interface{}{"2"}))
+	assert.Nil(t, format.EndTable(&buf1))
+	assert.Nil(t, clone.EndTable(&buf2))
+
+	assert.Equal(t, "# table: a\nid\n1\n", buf1.String())
+	assert.Equal(t, "# table: b\nid\n2\n", buf2.String())
+}
+
+func TestCSVFormatWithDirWritesOnePerTableFilePlusSchemaSQL(t *testing.T) {
+	dir := t.TempDir()
+	format := &CSVFormat{Dir: dir}
+
+	assert.Nil(t, format.WriteSchema(nil, "orders", "CREATE TABLE `orders` (`id` int)"))
+	assert.Nil(t, format.WriteSchema(nil, "users", "CREATE TABLE `users` (`id` int)"))
+
+	assert.Nil(t, format.BeginTable(nil, "orders", []string{"id"}))
+	assert.Nil(t, format.WriteRow(nil, []interface{}{"1"}))
+	assert.Nil(t, format.EndTable(nil))
+
+	assert.Nil(t, format.BeginTable(nil, "users", []string{"id"}))
+	assert.Nil(t, format.WriteRow(nil, []interface{}{"2"}))
+	assert.Nil(t, format.EndTable(nil))
+
+	ordersCSV, err := os.ReadFile(filepath.Join(dir, "orders.csv"))
+	assert.Nil(t, err)
+	assert.Equal(t, "id\n1\n", string(ordersCSV))
+
+	usersCSV, err := os.ReadFile(filepath.Join(dir, "users.csv"))
+	assert.Nil(t, err)
+	assert.Equal(t, "id\n2\n", string(usersCSV))
+
+	schemaSQL, err := os.ReadFile(filepath.Join(dir, "schema.sql"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(schemaSQL), "CREATE TABLE `orders` (`id` int)")
+	assert.Contains(t, string(schemaSQL), "CREATE TABLE `users` (`id` int)")
+}
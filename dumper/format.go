@@ -0,0 +1,54 @@
+package dumper
+
+import "io"
+
+// Format controls how schema and row data are rendered by WriteCreateTable
+// and writeRowsAsInserts. A nil value on Client falls back to &SQLFormat{},
+// the original dump format.
+type Format interface {
+	// WriteSchema writes the structure of a table given its CREATE TABLE
+	// statement as returned by SHOW CREATE TABLE.
+	WriteSchema(w io.Writer, table, ddl string) error
+
+	// BeginTable is called once before the first row of a table is
+	// written, with the column names in select order.
+	BeginTable(w io.Writer, table string, columns []string) error
+
+	// WriteRow is called once per row. Each value is either a string or
+	// nil for SQL NULL.
+	WriteRow(w io.Writer, values []interface{}) error
+
+	// EndTable is called once after the last row of a table has been
+	// written, to flush any buffered output and close the table section.
+	EndTable(w io.Writer) error
+
+	// Finalize is called once after every table, view, routine, trigger
+	// and event has been written.
+	Finalize(w io.Writer) error
+
+	// Clone returns a fresh instance carrying the same configuration but
+	// none of the per-table mutable state (buffered rows, open csv.Writer,
+	// ...). Concurrent chunk workers each need their own instance so they
+	// don't stomp on one another's state; see newChunkFormat.
+	Clone() Format
+}
+
+// format returns d.Format, defaulting to &SQLFormat{} when unset so callers
+// never need to nil-check. When d.Format is set, the returned instance is
+// shared across an entire WriteTables call and must not be handed to more
+// than one goroutine at a time; when it is unset, each call returns a new
+// SQLFormat. Concurrent chunk workers must use newChunkFormat instead.
+func (d *Client) format() Format {
+	if d.Format != nil {
+		return d.Format
+	}
+	return &SQLFormat{ExtendedInsertRows: d.ExtendedInsertRows}
+}
+
+// newChunkFormat returns a Format instance private to a single chunk
+// worker, so concurrent workers never share the mutable state (buffered
+// rows, open csv.Writer) that a stateful Format keeps between BeginTable and
+// EndTable.
+func (d *Client) newChunkFormat() Format {
+	return d.format().Clone()
+}
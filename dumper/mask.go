@@ -0,0 +1,174 @@
+package dumper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// SelectMapping describes how a single column's selected value is
+// produced: either a raw SQL expression substituted into the SELECT list
+// (SQL), the original SelectMap behavior, or the name of a Go-side masking
+// transform (Transform) applied to the scanned value before it is escaped.
+type SelectMapping struct {
+	// SQL is a raw SQL expression, e.g. "NOW()". Applied at query time by
+	// GetColumnsForSelect.
+	SQL string
+
+	// Transform is the name of a masking generator applied to the scanned
+	// value: fake_email, fake_phone, fake_name, hash_sha256, shift_days,
+	// or redact_keys.
+	Transform string
+
+	// Args configures the transform, e.g. {"days": "30"} for shift_days or
+	// {"keys": "email,phone"} for redact_keys.
+	Args map[string]string
+
+	// Seed makes the transform deterministic: the same input value always
+	// produces the same fake output, so foreign-key columns masked with
+	// the same seed stay referentially consistent across runs. It is
+	// deliberately not used to key hash_sha256 — Seed invites small,
+	// memorable values (e.g. 1) for reproducibility, which would be
+	// brute-forceable as an HMAC key for a low-cardinality column like an
+	// SSN. Use HMACKey for that instead.
+	Seed int64
+
+	// HMACKey is the secret that keys the hash_sha256 transform. It must
+	// be a real per-deployment secret of at least minHMACKeyBytes, not a
+	// small int: hash_sha256 exists so a low-cardinality column (a 9-digit
+	// SSN has ~1e9 possible values) can't be recovered offline by
+	// brute-forcing or a rainbow-table lookup, which a weak key would
+	// reintroduce.
+	HMACKey string
+}
+
+// minHMACKeyBytes is the minimum length enforced for SelectMapping.HMACKey.
+const minHMACKeyBytes = 16
+
+// applyTransform runs mapping.Transform against value, returning the
+// replacement. NULL values (value == nil) pass through untouched so masking
+// never turns a NULL into a fake value.
+func (d *Client) applyTransform(mapping SelectMapping, value *string) (*string, error) {
+	if value == nil || mapping.Transform == "" {
+		return value, nil
+	}
+
+	faker := gofakeit.New(seedFor(mapping.Seed, *value))
+
+	var out string
+
+	switch mapping.Transform {
+	case "fake_email":
+		out = faker.Email()
+	case "fake_phone":
+		out = faker.Phone()
+	case "fake_name":
+		out = faker.Name()
+	case "hash_sha256":
+		hashed, err := hmacSHA256(mapping.HMACKey, *value)
+		if err != nil {
+			return nil, err
+		}
+		out = hashed
+	case "shift_days":
+		shifted, err := shiftDays(*value, mapping.Args["days"])
+		if err != nil {
+			return nil, err
+		}
+		out = shifted
+	case "redact_keys":
+		redacted, err := redactKeys(*value, mapping.Args["keys"])
+		if err != nil {
+			return nil, err
+		}
+		out = redacted
+	default:
+		return nil, fmt.Errorf("dumper: unknown transform %q", mapping.Transform)
+	}
+
+	return &out, nil
+}
+
+// seedFor derives a deterministic RNG seed from a base seed and an input
+// value, so the same (seed, value) pair always produces the same fake
+// output across runs.
+func seedFor(base int64, value string) int64 {
+	sum := sha256.Sum256([]byte(value))
+	return base ^ int64(binary.BigEndian.Uint64(sum[:8]))
+}
+
+// hmacSHA256 keys the hash with key instead of hashing the value alone. A
+// bare sha256(value) is reversible offline for any low-cardinality column
+// (e.g. a 9-digit SSN has ~1e9 possible values) via a brute-force or
+// rainbow-table lookup, defeating the point of masking it; keying the hash
+// closes that off, provided the key itself has enough entropy to resist
+// the same kind of brute-forcing.
+func hmacSHA256(key, value string) (string, error) {
+	if len(key) < minHMACKeyBytes {
+		return "", fmt.Errorf("dumper: hash_sha256 requires SelectMapping.HMACKey of at least %d bytes, got %d", minHMACKeyBytes, len(key))
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// shiftDays parses value as a date or datetime and shifts it by the number
+// of days given in arg (which may be negative), preserving the original
+// layout.
+func shiftDays(value, arg string) (string, error) {
+	days, err := strconv.Atoi(arg)
+	if err != nil {
+		return "", fmt.Errorf("dumper: invalid shift_days arg %q: %w", arg, err)
+	}
+
+	const dateLayout = "2006-01-02"
+	const dateTimeLayout = "2006-01-02 15:04:05"
+
+	if t, err := time.Parse(dateTimeLayout, value); err == nil {
+		return t.AddDate(0, 0, days).Format(dateTimeLayout), nil
+	}
+
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return "", fmt.Errorf("dumper: cannot parse %q for shift_days: %w", value, err)
+	}
+
+	return t.AddDate(0, 0, days).Format(dateLayout), nil
+}
+
+// redactKeys parses value as a JSON object and replaces each key listed in
+// the comma-separated arg with the literal string "REDACTED".
+func redactKeys(value, arg string) (string, error) {
+	if arg == "" {
+		return value, nil
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &doc); err != nil {
+		return "", fmt.Errorf("dumper: redact_keys: invalid JSON: %w", err)
+	}
+
+	for _, key := range strings.Split(arg, ",") {
+		key = strings.TrimSpace(key)
+		if _, ok := doc[key]; ok {
+			doc[key] = "REDACTED"
+		}
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
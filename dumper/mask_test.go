@@ -0,0 +1,82 @@
+package dumper
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDumper(t *testing.T) *Client {
+	db, _, err := sqlmock.New()
+	assert.Nil(t, err)
+	t.Cleanup(func() { db.Close() })
+	return NewMySQLDumper(db)
+}
+
+func TestApplyTransformPassesThroughNilValues(t *testing.T) {
+	dumper := newTestDumper(t)
+
+	out, err := dumper.applyTransform(SelectMapping{Transform: "hash_sha256"}, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+}
+
+func TestApplyTransformHashSHA256IsKeyedByHMACKeyNotSeed(t *testing.T) {
+	dumper := newTestDumper(t)
+	value := "123-45-6789"
+
+	a, err := dumper.applyTransform(SelectMapping{Transform: "hash_sha256", HMACKey: "0123456789abcdef"}, &value)
+	assert.Nil(t, err)
+	b, err := dumper.applyTransform(SelectMapping{Transform: "hash_sha256", HMACKey: "0123456789abcdef"}, &value)
+	assert.Nil(t, err)
+	c, err := dumper.applyTransform(SelectMapping{Transform: "hash_sha256", HMACKey: "fedcba9876543210"}, &value)
+	assert.Nil(t, err)
+
+	assert.Equal(t, *a, *b)
+	assert.NotEqual(t, *a, *c)
+	assert.NotEqual(t, value, *a)
+}
+
+func TestApplyTransformHashSHA256RejectsAShortHMACKey(t *testing.T) {
+	dumper := newTestDumper(t)
+	value := "123-45-6789"
+
+	_, err := dumper.applyTransform(SelectMapping{Transform: "hash_sha256", HMACKey: "short"}, &value)
+	assert.NotNil(t, err)
+}
+
+func TestApplyTransformShiftDaysPreservesDateLayout(t *testing.T) {
+	dumper := newTestDumper(t)
+	value := "2020-01-01"
+
+	out, err := dumper.applyTransform(SelectMapping{Transform: "shift_days", Args: map[string]string{"days": "-1"}}, &value)
+	assert.Nil(t, err)
+	assert.Equal(t, "2019-12-31", *out)
+}
+
+func TestApplyTransformShiftDaysRejectsUnparseableValue(t *testing.T) {
+	dumper := newTestDumper(t)
+	value := "not-a-date"
+
+	_, err := dumper.applyTransform(SelectMapping{Transform: "shift_days", Args: map[string]string{"days": "1"}}, &value)
+	assert.NotNil(t, err)
+}
+
+func TestApplyTransformRedactKeysReplacesOnlyListedKeys(t *testing.T) {
+	dumper := newTestDumper(t)
+	value := `{"email":"a@example.com","age":30}`
+
+	out, err := dumper.applyTransform(SelectMapping{Transform: "redact_keys", Args: map[string]string{"keys": "email"}}, &value)
+	assert.Nil(t, err)
+	assert.Contains(t, *out, `"email":"REDACTED"`)
+	assert.Contains(t, *out, `"age":30`)
+}
+
+func TestApplyTransformUnknownTransformErrors(t *testing.T) {
+	dumper := newTestDumper(t)
+	value := "x"
+
+	_, err := dumper.applyTransform(SelectMapping{Transform: "nope"}, &value)
+	assert.NotNil(t, err)
+}
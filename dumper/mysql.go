@@ -1,6 +1,7 @@
 package dumper
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"io"
@@ -20,12 +21,45 @@ const (
 
 // Client used for dumping a database and/or table.
 type Client struct {
-	DB                 *sql.DB
-	SelectMap          map[string]map[string]string
+	DB *sql.DB
+	// SelectMap maps table -> column -> SelectMapping, configuring either a
+	// raw SQL substitution or a Go-side masking transform per column.
+	SelectMap          map[string]map[string]SelectMapping
 	WhereMap           map[string]string
 	FilterMap          map[string]string
 	UseTableLock       bool
 	ExtendedInsertRows int
+
+	// Concurrency is the number of workers used to dump a table's rows in
+	// parallel, split into chunks of RowsPerChunk rows. Values <= 1 keep
+	// the original single-threaded WriteTableData behavior.
+	Concurrency int
+
+	// RowsPerChunk is the maximum number of rows fetched per chunk query
+	// when Concurrency > 1. Defaults to DefaultRowsPerChunk when zero.
+	RowsPerChunk uint64
+
+	// ConsistencyMode controls how table locking/snapshotting is performed
+	// while dumping. One of ConsistencyModeNone, ConsistencyModeFlushLock
+	// (the default/original behavior) or ConsistencyModeSnapshot.
+	ConsistencyMode string
+
+	// conn is the dedicated connection used for the whole dump while a
+	// ConsistencyModeSnapshot transaction is open. Set by beginSnapshot and
+	// cleared by endSnapshot; nil otherwise.
+	conn *sql.Conn
+
+	// Format controls how schema and row data are rendered. Defaults to
+	// &SQLFormat{} when nil.
+	Format Format
+
+	// Routines, Triggers and Events gate dumping stored procedures and
+	// functions, triggers, and events, mirroring the equivalent mysqldump
+	// flags. NoViews disables dumping views, which are otherwise included.
+	Routines bool
+	Triggers bool
+	Events   bool
+	NoViews  bool
 }
 
 // NewMySQLDumper is the constructor
@@ -33,29 +67,59 @@ func NewMySQLDumper(db *sql.DB) *Client {
 	return &Client{
 		DB:                 db,
 		ExtendedInsertRows: DefaultExtendedInsertRows,
+		// mysqldump dumps triggers by default (--skip-triggers opts out);
+		// Routines and Events default off, matching mysqldump's own
+		// defaults for --routines and --events.
+		Triggers: true,
+	}
+}
+
+// query runs query against d.conn when a ConsistencyModeSnapshot transaction
+// is open, so every statement in the dump sees the same point-in-time view;
+// otherwise it runs directly against the pool.
+func (d *Client) query(query string, args ...interface{}) (*sql.Rows, error) {
+	if d.conn != nil {
+		return d.conn.QueryContext(context.Background(), query, args...)
 	}
+	return d.DB.Query(query, args...)
+}
+
+// queryRow is the *sql.Row counterpart of query.
+func (d *Client) queryRow(query string, args ...interface{}) *sql.Row {
+	if d.conn != nil {
+		return d.conn.QueryRowContext(context.Background(), query, args...)
+	}
+	return d.DB.QueryRow(query, args...)
+}
+
+// exec is the sql.Result counterpart of query.
+func (d *Client) exec(query string, args ...interface{}) (sql.Result, error) {
+	if d.conn != nil {
+		return d.conn.ExecContext(context.Background(), query, args...)
+	}
+	return d.DB.Exec(query, args...)
 }
 
 // LockTableReading explicitly acquires table locks for the current client session.
 func (d *Client) LockTableReading(table string) (sql.Result, error) {
-	return d.DB.Exec(fmt.Sprintf("LOCK TABLES `%s` READ", table))
+	return d.exec(fmt.Sprintf("LOCK TABLES `%s` READ", table))
 }
 
 // FlushTable will force a tables to be closed.
 func (d *Client) FlushTable(table string) (sql.Result, error) {
-	return d.DB.Exec(fmt.Sprintf("FLUSH TABLES `%s`", table))
+	return d.exec(fmt.Sprintf("FLUSH TABLES `%s`", table))
 }
 
 // UnlockTables explicitly releases any table locks held by the current session.
 func (d *Client) UnlockTables() (sql.Result, error) {
-	return d.DB.Exec("UNLOCK TABLES")
+	return d.exec("UNLOCK TABLES")
 }
 
 // GetTables will return a list of tables.
 func (d *Client) GetTables() ([]string, error) {
 	tables := make([]string, 0)
 
-	rows, err := d.DB.Query("SHOW FULL TABLES")
+	rows, err := d.query("SHOW FULL TABLES")
 	if err != nil {
 		return tables, err
 	}
@@ -80,10 +144,7 @@ func (d *Client) GetTables() ([]string, error) {
 
 // WriteCreateTable script used when dumping a database.
 func (d *Client) WriteCreateTable(w io.Writer, table string) error {
-	fmt.Fprintf(w, "\n--\n-- Structure for table `%s`\n--\n\n", table)
-	fmt.Fprintf(w, "DROP TABLE IF EXISTS `%s`;\n", table)
-
-	row := d.DB.QueryRow(fmt.Sprintf("SHOW CREATE TABLE `%s`", table))
+	row := d.queryRow(fmt.Sprintf("SHOW CREATE TABLE `%s`", table))
 
 	var name, ddl string
 
@@ -91,16 +152,14 @@ func (d *Client) WriteCreateTable(w io.Writer, table string) error {
 		return err
 	}
 
-	fmt.Fprintf(w, "%s;\n", ddl)
-
-	return nil
+	return d.format().WriteSchema(w, table, ddl)
 }
 
 // GetColumnsForSelect for applying the select map from config.
 func (d *Client) GetColumnsForSelect(table string) ([]string, error) {
 	var rows *sql.Rows
 
-	rows, err := d.DB.Query(fmt.Sprintf("SELECT * FROM `%s` LIMIT 1", table))
+	rows, err := d.query(fmt.Sprintf("SELECT * FROM `%s` LIMIT 1", table))
 	if err != nil {
 		return nil, err
 	}
@@ -113,9 +172,9 @@ func (d *Client) GetColumnsForSelect(table string) ([]string, error) {
 	}
 
 	for k, column := range columns {
-		replacement, ok := d.SelectMap[strings.ToLower(table)][strings.ToLower(column)]
-		if ok {
-			columns[k] = fmt.Sprintf("%s AS `%s`", replacement, column)
+		mapping, ok := d.SelectMap[strings.ToLower(table)][strings.ToLower(column)]
+		if ok && mapping.SQL != "" {
+			columns[k] = fmt.Sprintf("%s AS `%s`", mapping.SQL, column)
 		} else {
 			columns[k] = fmt.Sprintf("`%s`", column)
 		}
@@ -146,7 +205,7 @@ func (d *Client) GetRowCountForTable(table string) (count uint64, err error) {
 	if where, ok := d.WhereMap[strings.ToLower(table)]; ok {
 		query = fmt.Sprintf("%s WHERE %s", query, where)
 	}
-	row := d.DB.QueryRow(query)
+	row := d.queryRow(query)
 	if err = row.Scan(&count); err != nil {
 		return
 	}
@@ -170,7 +229,7 @@ func (d *Client) selectAllDataFor(table string) (*sql.Rows, []string, error) {
 		return nil, nil, err
 	}
 
-	rows, err := d.DB.Query(query)
+	rows, err := d.query(query)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -197,15 +256,42 @@ func (d *Client) WriteTableHeader(w io.Writer, table string) (uint64, error) {
 	return count, nil
 }
 
-// WriteTableData for a specific table.
+// WriteTableData for a specific table. When Concurrency > 1 the table is
+// split into chunks and dumped by a worker pool; otherwise rows are
+// streamed sequentially. Concurrency is ignored in ConsistencyModeSnapshot:
+// every query in that mode runs on the single dedicated *sql.Conn opened by
+// beginSnapshot, which MySQL (and *sql.Conn) only ever process one
+// statement at a time on, so concurrent chunk workers would just corrupt
+// each other's results.
 func (d *Client) WriteTableData(w io.Writer, table string) error {
+	if d.Concurrency > 1 && d.ConsistencyMode != ConsistencyModeSnapshot {
+		return d.writeTableDataConcurrent(w, table)
+	}
+
 	rows, columns, err := d.selectAllDataFor(table)
 	if err != nil {
 		return err
 	}
 
+	return d.writeRowsAsInserts(w, rows, columns, table, d.format())
+}
+
+// writeRowsAsInserts renders the remaining rows of an open *sql.Rows
+// through format, for table. It is shared by the sequential and chunked
+// dump paths so both render rows the same way; the chunked path calls it
+// once per chunk, so SQL INSERT batches end at chunk boundaries. Callers that may run
+// concurrently (chunk.go) must each pass their own Format instance — see
+// newChunkFormat — since Format implementations keep per-table mutable
+// state between BeginTable and EndTable.
+func (d *Client) writeRowsAsInserts(w io.Writer, rows *sql.Rows, columns []string, table string, format Format) error {
 	defer rows.Close()
 
+	if err := format.BeginTable(w, table, columns); err != nil {
+		return err
+	}
+
+	mappings := d.SelectMap[strings.ToLower(table)]
+
 	values := make([]*sql.RawBytes, len(columns))
 	scanArgs := make([]interface{}, len(values))
 
@@ -213,48 +299,67 @@ func (d *Client) WriteTableData(w io.Writer, table string) error {
 		scanArgs[i] = &values[i]
 	}
 
-	query := fmt.Sprintf("INSERT INTO `%s` VALUES", table)
-
-	var data []string
-
 	for rows.Next() {
-		if err = rows.Scan(scanArgs...); err != nil {
+		if err := rows.Scan(scanArgs...); err != nil {
 			return err
 		}
 
-		var vals []string
-
-		for _, col := range values {
-			val := "NULL"
+		vals := make([]interface{}, len(values))
 
+		for i, col := range values {
+			var strVal *string
 			if col != nil {
-				val = fmt.Sprintf("'%s'", escape(string(*col)))
+				s := string(*col)
+				strVal = &s
 			}
 
-			vals = append(vals, val)
-		}
-
-		data = append(data, fmt.Sprintf("( %s )", strings.Join(vals, ", ")))
+			if mapping, ok := mappings[strings.ToLower(columns[i])]; ok {
+				transformed, err := d.applyTransform(mapping, strVal)
+				if err != nil {
+					return err
+				}
+				strVal = transformed
+			}
 
-		if d.ExtendedInsertRows == 0 {
-			continue
+			if strVal != nil {
+				vals[i] = *strVal
+			}
 		}
 
-		if len(data) >= d.ExtendedInsertRows {
-			fmt.Fprintf(w, "%s\n%s;\n", query, strings.Join(data, ",\n"))
-			data = make([]string, 0)
+		if err := format.WriteRow(w, vals); err != nil {
+			return err
 		}
 	}
 
-	if len(data) > 0 {
-		fmt.Fprintf(w, "%s\n%s;\n", query, strings.Join(data, ",\n"))
-	}
-
-	return nil
+	return format.EndTable(w)
 }
 
-// WriteTables will create a script for all tables.
+// WriteTables will create a script for all tables. In ConsistencyModeSnapshot
+// a single consistent-snapshot transaction is opened first so every table is
+// read from the same point-in-time view.
 func (d *Client) WriteTables(w io.Writer) error {
+	if d.ConsistencyMode == ConsistencyModeSnapshot {
+		if err := d.beginSnapshot(w); err != nil {
+			return err
+		}
+		defer d.endSnapshot()
+	}
+
+	var views []string
+	if !d.NoViews {
+		v, err := d.GetViews()
+		if err != nil {
+			return err
+		}
+
+		views = v
+		for _, view := range views {
+			if err := d.WriteViewStub(w, view); err != nil {
+				return err
+			}
+		}
+	}
+
 	tables, err := d.GetTables()
 	if err != nil {
 		return err
@@ -266,17 +371,46 @@ func (d *Client) WriteTables(w io.Writer) error {
 		}
 	}
 
-	return nil
+	for _, view := range views {
+		if err := d.WriteView(w, view); err != nil {
+			return err
+		}
+	}
+
+	if err := d.WriteRoutines(w); err != nil {
+		return err
+	}
+
+	if err := d.WriteTriggers(w); err != nil {
+		return err
+	}
+
+	if err := d.WriteEvents(w); err != nil {
+		return err
+	}
+
+	return d.format().Finalize(w)
 }
 
-// WriteTable allows for a single table dump script.
+// WriteTable allows for a single table dump script. Table locking is
+// skipped entirely in ConsistencyModeNone, which performs no locking at
+// all, in ConsistencyModeSnapshot, since the consistent
+// snapshot transaction already guarantees a stable read, and whenever
+// Concurrency > 1: LockTableReading/FlushTable borrow one connection from
+// the pool and release it immediately, but the chunk workers spawned by
+// WriteTableData each run on whatever connection the pool hands them, so a
+// table-level READ lock held this way provides no real guarantee once
+// those reads are split across connections.
 func (d *Client) WriteTable(w io.Writer, table string) error {
 	if d.FilterMap[strings.ToLower(table)] == OperationIgnore {
 		return nil
 	}
 
+	useLock := d.UseTableLock && d.ConsistencyMode != ConsistencyModeNone &&
+		d.ConsistencyMode != ConsistencyModeSnapshot && d.Concurrency <= 1
+
 	skipData := d.FilterMap[strings.ToLower(table)] == OperationNoData
-	if !skipData && d.UseTableLock {
+	if !skipData && useLock {
 		d.LockTableReading(table)
 		d.FlushTable(table)
 	}
@@ -296,7 +430,7 @@ func (d *Client) WriteTable(w io.Writer, table string) error {
 			fmt.Fprintln(w)
 
 			d.WriteUnlockTables(w)
-			if d.UseTableLock {
+			if useLock {
 				d.UnlockTables()
 			}
 		}
@@ -0,0 +1,297 @@
+package dumper
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// queryColumn runs query and returns every row's value for the named
+// column, using the result set's own column list to locate it. It is a
+// small generic helper for the various "SHOW ... STATUS"/"SHOW TRIGGERS"
+// queries below, whose column sets differ by MySQL version.
+func (d *Client) queryColumn(query, column string) ([]string, error) {
+	rows, err := d.query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	idx := -1
+	for i, c := range cols {
+		if c == column {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return nil, fmt.Errorf("dumper: column %q not found in result of %q", column, query)
+	}
+
+	values := make([]string, 0)
+
+	for rows.Next() {
+		row := make([]sql.RawBytes, len(cols))
+		scanArgs := make([]interface{}, len(cols))
+		for i := range row {
+			scanArgs[i] = &row[i]
+		}
+
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, err
+		}
+
+		values = append(values, string(row[idx]))
+	}
+
+	return values, nil
+}
+
+// GetViews will return a list of views.
+func (d *Client) GetViews() ([]string, error) {
+	views := make([]string, 0)
+
+	rows, err := d.query("SHOW FULL TABLES")
+	if err != nil {
+		return views, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, tableType string
+
+		if err := rows.Scan(&tableName, &tableType); err != nil {
+			return views, err
+		}
+
+		if tableType == "VIEW" {
+			views = append(views, tableName)
+		}
+	}
+
+	return views, nil
+}
+
+// WriteViewStub writes a placeholder base table standing in for a view, so
+// that other views or tables referencing it can still be created before the
+// real CREATE VIEW is emitted later in the dump. This mirrors mysqldump's
+// two-pass handling of views, which breaks circular view dependencies.
+func (d *Client) WriteViewStub(w io.Writer, view string) error {
+	rows, err := d.query(fmt.Sprintf("SELECT * FROM `%s` LIMIT 1", view))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	defs := make([]string, len(columns))
+	for i, col := range columns {
+		defs[i] = fmt.Sprintf("`%s` int", col)
+	}
+
+	fmt.Fprintf(w, "\n--\n-- Temporary table structure for view `%s`\n--\n\n", view)
+	fmt.Fprintf(w, "DROP VIEW IF EXISTS `%s`;\n", view)
+	fmt.Fprintf(w, "CREATE TABLE `%s` (\n  %s\n);\n", view, strings.Join(defs, ",\n  "))
+
+	return nil
+}
+
+// WriteView writes the final CREATE VIEW statement for view, replacing the
+// stub table written by WriteViewStub.
+func (d *Client) WriteView(w io.Writer, view string) error {
+	row := d.queryRow(fmt.Sprintf("SHOW CREATE VIEW `%s`", view))
+
+	var name, ddl, charset, collation string
+
+	if err := row.Scan(&name, &ddl, &charset, &collation); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n--\n-- Final view structure for view `%s`\n--\n\n", view)
+	fmt.Fprintf(w, "DROP TABLE IF EXISTS `%s`;\n", view)
+	fmt.Fprintf(w, "DROP VIEW IF EXISTS `%s`;\n", view)
+	fmt.Fprintf(w, "%s;\n", ddl)
+
+	return nil
+}
+
+// GetProcedures will return a list of stored procedures in the current database.
+func (d *Client) GetProcedures() ([]string, error) {
+	return d.queryColumn("SHOW PROCEDURE STATUS WHERE Db = DATABASE()", "Name")
+}
+
+// GetFunctions will return a list of stored functions in the current database.
+func (d *Client) GetFunctions() ([]string, error) {
+	return d.queryColumn("SHOW FUNCTION STATUS WHERE Db = DATABASE()", "Name")
+}
+
+// GetTriggers will return a list of triggers in the current database.
+func (d *Client) GetTriggers() ([]string, error) {
+	return d.queryColumn("SHOW TRIGGERS", "Trigger")
+}
+
+// GetEvents will return a list of events in the current database.
+func (d *Client) GetEvents() ([]string, error) {
+	return d.queryColumn("SHOW EVENTS", "Name")
+}
+
+// WriteProcedure writes a DROP PROCEDURE/CREATE PROCEDURE block for name,
+// wrapped in a DELIMITER block so the body's own semicolons don't terminate
+// the statement early when loaded via the mysql client.
+func (d *Client) WriteProcedure(w io.Writer, name string) error {
+	row := d.queryRow(fmt.Sprintf("SHOW CREATE PROCEDURE `%s`", name))
+
+	var procName, sqlMode, ddl, charset, collation, collationConn string
+
+	if err := row.Scan(&procName, &sqlMode, &ddl, &charset, &collation, &collationConn); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n--\n-- Procedure structure for `%s`\n--\n\n", name)
+	fmt.Fprintf(w, "DROP PROCEDURE IF EXISTS `%s`;\n", name)
+	fmt.Fprintln(w, "DELIMITER $$")
+	fmt.Fprintf(w, "%s$$\n", ddl)
+	fmt.Fprintln(w, "DELIMITER ;")
+
+	return nil
+}
+
+// WriteFunction writes a DROP FUNCTION/CREATE FUNCTION block for name,
+// wrapped in a DELIMITER block.
+func (d *Client) WriteFunction(w io.Writer, name string) error {
+	row := d.queryRow(fmt.Sprintf("SHOW CREATE FUNCTION `%s`", name))
+
+	var funcName, sqlMode, ddl, charset, collation, collationConn string
+
+	if err := row.Scan(&funcName, &sqlMode, &ddl, &charset, &collation, &collationConn); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n--\n-- Function structure for `%s`\n--\n\n", name)
+	fmt.Fprintf(w, "DROP FUNCTION IF EXISTS `%s`;\n", name)
+	fmt.Fprintln(w, "DELIMITER $$")
+	fmt.Fprintf(w, "%s$$\n", ddl)
+	fmt.Fprintln(w, "DELIMITER ;")
+
+	return nil
+}
+
+// WriteTrigger writes a DROP TRIGGER/CREATE TRIGGER block for name, wrapped
+// in a DELIMITER block.
+func (d *Client) WriteTrigger(w io.Writer, name string) error {
+	row := d.queryRow(fmt.Sprintf("SHOW CREATE TRIGGER `%s`", name))
+
+	var trigName, sqlMode, ddl, charset, collation, dbCollation string
+
+	if err := row.Scan(&trigName, &sqlMode, &ddl, &charset, &collation, &dbCollation); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n--\n-- Trigger structure for `%s`\n--\n\n", name)
+	fmt.Fprintf(w, "DROP TRIGGER IF EXISTS `%s`;\n", name)
+	fmt.Fprintln(w, "DELIMITER $$")
+	fmt.Fprintf(w, "%s$$\n", ddl)
+	fmt.Fprintln(w, "DELIMITER ;")
+
+	return nil
+}
+
+// WriteEvent writes a DROP EVENT/CREATE EVENT block for name, wrapped in a
+// DELIMITER block.
+func (d *Client) WriteEvent(w io.Writer, name string) error {
+	row := d.queryRow(fmt.Sprintf("SHOW CREATE EVENT `%s`", name))
+
+	var eventName, sqlMode, timeZone, ddl, charset, collation, dbCollation string
+
+	if err := row.Scan(&eventName, &sqlMode, &timeZone, &ddl, &charset, &collation, &dbCollation); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n--\n-- Event structure for `%s`\n--\n\n", name)
+	fmt.Fprintf(w, "DROP EVENT IF EXISTS `%s`;\n", name)
+	fmt.Fprintln(w, "DELIMITER $$")
+	fmt.Fprintf(w, "%s$$\n", ddl)
+	fmt.Fprintln(w, "DELIMITER ;")
+
+	return nil
+}
+
+// WriteRoutines dumps stored procedures and functions, gated by Routines.
+func (d *Client) WriteRoutines(w io.Writer) error {
+	if !d.Routines {
+		return nil
+	}
+
+	procedures, err := d.GetProcedures()
+	if err != nil {
+		return err
+	}
+	for _, name := range procedures {
+		if err := d.WriteProcedure(w, name); err != nil {
+			return err
+		}
+	}
+
+	functions, err := d.GetFunctions()
+	if err != nil {
+		return err
+	}
+	for _, name := range functions {
+		if err := d.WriteFunction(w, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteTriggers dumps triggers, gated by Triggers.
+func (d *Client) WriteTriggers(w io.Writer) error {
+	if !d.Triggers {
+		return nil
+	}
+
+	triggers, err := d.GetTriggers()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range triggers {
+		if err := d.WriteTrigger(w, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteEvents dumps events, gated by Events.
+func (d *Client) WriteEvents(w io.Writer) error {
+	if !d.Events {
+		return nil
+	}
+
+	events, err := d.GetEvents()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range events {
+		if err := d.WriteEvent(w, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
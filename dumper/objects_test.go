@@ -0,0 +1,74 @@
+package dumper
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMySQLDumperDefaultsTriggersOnAndRoutinesEventsOff(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	dumper := NewMySQLDumper(db)
+
+	assert.True(t, dumper.Triggers)
+	assert.False(t, dumper.Routines)
+	assert.False(t, dumper.Events)
+}
+
+func TestGetViewsFiltersOutBaseTables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	dumper := NewMySQLDumper(db)
+
+	mock.ExpectQuery("SHOW FULL TABLES").WillReturnRows(
+		sqlmock.NewRows([]string{"Tables_in_db", "Table_type"}).
+			AddRow("orders", "BASE TABLE").
+			AddRow("orders_v", "VIEW"),
+	)
+
+	views, err := dumper.GetViews()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"orders_v"}, views)
+}
+
+func TestWriteTriggerWrapsDDLInDelimiterBlock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	dumper := NewMySQLDumper(db)
+
+	mock.ExpectQuery("SHOW CREATE TRIGGER `trg`").WillReturnRows(
+		sqlmock.NewRows([]string{"Trigger", "sql_mode", "SQL Original Statement", "character_set_client", "collation_connection", "Database Collation"}).
+			AddRow("trg", "", "CREATE TRIGGER `trg` BEFORE INSERT ON `orders` FOR EACH ROW SET NEW.id = 1", "utf8", "utf8_general_ci", "utf8_general_ci"),
+	)
+
+	var buf bytes.Buffer
+	assert.Nil(t, dumper.WriteTrigger(&buf, "trg"))
+
+	out := buf.String()
+	assert.Contains(t, out, "DROP TRIGGER IF EXISTS `trg`;")
+	assert.Contains(t, out, "DELIMITER $$")
+	assert.Contains(t, out, "CREATE TRIGGER `trg` BEFORE INSERT ON `orders` FOR EACH ROW SET NEW.id = 1$$")
+	assert.Contains(t, out, "DELIMITER ;")
+}
+
+func TestWriteTriggersSkipsEntirelyWhenDisabled(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	dumper := NewMySQLDumper(db)
+	dumper.Triggers = false
+
+	var buf bytes.Buffer
+	assert.Nil(t, dumper.WriteTriggers(&buf))
+	assert.Equal(t, "", buf.String())
+}
@@ -0,0 +1,64 @@
+package dumper
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeginSnapshotWritesBinlogHeaderAndPinsConn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("START TRANSACTION WITH CONSISTENT SNAPSHOT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SHOW MASTER STATUS").WillReturnRows(
+		sqlmock.NewRows([]string{"File", "Position", "Binlog_Do_DB", "Binlog_Ignore_DB", "Executed_Gtid_Set"}).
+			AddRow("binlog.000001", 154, "", "", "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5"),
+	)
+
+	dumper := NewMySQLDumper(db)
+
+	var buf bytes.Buffer
+	assert.Nil(t, dumper.beginSnapshot(&buf))
+	assert.NotNil(t, dumper.conn)
+	assert.Contains(t, buf.String(), "binlog.000001:154")
+	assert.Contains(t, buf.String(), "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5")
+
+	mock.ExpectExec("COMMIT").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Nil(t, dumper.endSnapshot())
+	assert.Nil(t, dumper.conn)
+}
+
+func TestEndSnapshotIsNoopWithoutAnOpenSnapshot(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	dumper := NewMySQLDumper(db)
+	assert.Nil(t, dumper.endSnapshot())
+}
+
+func TestWriteTableDataRunsSequentiallyInSnapshotModeEvenWithConcurrencySet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	dumper := NewMySQLDumper(db)
+	dumper.Concurrency = 4
+	dumper.ConsistencyMode = ConsistencyModeSnapshot
+
+	mock.ExpectQuery("SELECT \\* FROM `table` LIMIT 1").WillReturnRows(
+		sqlmock.NewRows([]string{"col1"}).AddRow("a"),
+	)
+	mock.ExpectQuery("SELECT `col1` FROM `table`").WillReturnRows(
+		sqlmock.NewRows([]string{"col1"}).AddRow("a"),
+	)
+
+	var buf bytes.Buffer
+	assert.Nil(t, dumper.WriteTableData(&buf, "table"))
+	assert.Contains(t, buf.String(), "INSERT INTO `table` VALUES")
+}
@@ -0,0 +1,81 @@
+package dumper
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SQLFormat renders schema and data as the original mysqldump-style SQL
+// script: DROP TABLE/CREATE TABLE for schema, and extended INSERT
+// statements batched up to ExtendedInsertRows rows each.
+type SQLFormat struct {
+	// ExtendedInsertRows is the number of rows batched per INSERT
+	// statement. Zero or less puts all of a table's rows into a single
+	// INSERT statement.
+	ExtendedInsertRows int
+
+	table string
+	rows  []string
+}
+
+// WriteSchema implements Format.
+func (f *SQLFormat) WriteSchema(w io.Writer, table, ddl string) error {
+	fmt.Fprintf(w, "\n--\n-- Structure for table `%s`\n--\n\n", table)
+	fmt.Fprintf(w, "DROP TABLE IF EXISTS `%s`;\n", table)
+	fmt.Fprintf(w, "%s;\n", ddl)
+	return nil
+}
+
+// BeginTable implements Format.
+func (f *SQLFormat) BeginTable(w io.Writer, table string, columns []string) error {
+	f.table = table
+	f.rows = f.rows[:0]
+	return nil
+}
+
+// WriteRow implements Format.
+func (f *SQLFormat) WriteRow(w io.Writer, values []interface{}) error {
+	vals := make([]string, len(values))
+
+	for i, v := range values {
+		if v == nil {
+			vals[i] = "NULL"
+		} else {
+			vals[i] = fmt.Sprintf("'%s'", escape(v.(string)))
+		}
+	}
+
+	f.rows = append(f.rows, fmt.Sprintf("( %s )", strings.Join(vals, ", ")))
+
+	if f.ExtendedInsertRows > 0 && len(f.rows) >= f.ExtendedInsertRows {
+		f.flush(w)
+	}
+
+	return nil
+}
+
+// EndTable implements Format.
+func (f *SQLFormat) EndTable(w io.Writer) error {
+	f.flush(w)
+	return nil
+}
+
+// Finalize implements Format.
+func (f *SQLFormat) Finalize(w io.Writer) error {
+	return nil
+}
+
+// Clone implements Format.
+func (f *SQLFormat) Clone() Format {
+	return &SQLFormat{ExtendedInsertRows: f.ExtendedInsertRows}
+}
+
+func (f *SQLFormat) flush(w io.Writer) {
+	if len(f.rows) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "INSERT INTO `%s` VALUES\n%s;\n", f.table, strings.Join(f.rows, ",\n"))
+	f.rows = f.rows[:0]
+}
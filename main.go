@@ -3,6 +3,7 @@ package main
 import (
 	"database/sql"
 	"log"
+	"os"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/hgfischer/mysqlsuperdump/dumper"
@@ -29,6 +30,30 @@ func main() {
 	dumpr.FilterMap = cfg.filterMap
 	dumpr.UseTableLock = cfg.useTableLock
 	dumpr.ExtendedInsertRows = cfg.extendedInsRows
+	dumpr.Concurrency = cfg.concurrency
+	dumpr.RowsPerChunk = cfg.rowsPerChunk
+	dumpr.ConsistencyMode = cfg.consistencyMode
+	dumpr.Routines = cfg.routines
+	dumpr.Triggers = cfg.triggers
+	dumpr.Events = cfg.events
+	dumpr.NoViews = cfg.noViews
+
+	switch cfg.format {
+	case "csv":
+		csvFormat := &dumper.CSVFormat{Dir: cfg.outDir}
+		if cfg.outDir == "" {
+			// No output directory configured, so every table's CSV goes to
+			// the single main stream; open a companion schema.sql in the
+			// working directory so CREATE TABLE DDL isn't silently dropped.
+			schema, err := os.Create("schema.sql")
+			checkError(err)
+			defer schema.Close()
+			csvFormat.Schema = schema
+		}
+		dumpr.Format = csvFormat
+	default:
+		dumpr.Format = &dumper.SQLFormat{ExtendedInsertRows: cfg.extendedInsRows}
+	}
 
 	w, err := cfg.initOutput()
 	checkError(err)